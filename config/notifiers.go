@@ -0,0 +1,303 @@
+// Copyright 2019 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	commoncfg "github.com/prometheus/common/config"
+)
+
+// NotifierConfig contains base options common across all notifier
+// configurations.
+type NotifierConfig struct {
+	VSendResolved bool `yaml:"send_resolved" json:"send_resolved"`
+}
+
+// SendResolved returns whether the notifier should send resolved
+// notifications.
+func (nc *NotifierConfig) SendResolved() bool {
+	return nc.VSendResolved
+}
+
+// EventProtocol identifies which CloudEvents transport binding an
+// EventConfig receiver speaks.
+type EventProtocol string
+
+// Supported event protocols.
+const (
+	ProtocolHTTP      EventProtocol = "http"
+	ProtocolKafka     EventProtocol = "kafka"
+	ProtocolNATS      EventProtocol = "nats"
+	ProtocolMQTT      EventProtocol = "mqtt"
+	ProtocolGCPPubSub EventProtocol = "gcppubsub"
+)
+
+// EventContentMode selects how CloudEvents attributes are carried over HTTP.
+type EventContentMode string
+
+// Supported HTTP content modes.
+const (
+	ContentModeBinary     EventContentMode = "binary"
+	ContentModeStructured EventContentMode = "structured"
+)
+
+// KafkaSASLConfig configures SASL authentication against a Kafka broker.
+type KafkaSASLConfig struct {
+	Mechanism string           `yaml:"mechanism,omitempty" json:"mechanism,omitempty"`
+	Username  string           `yaml:"username,omitempty" json:"username,omitempty"`
+	Password  commoncfg.Secret `yaml:"password,omitempty" json:"password,omitempty"`
+}
+
+// KafkaConfig configures the Kafka CloudEvents protocol binding.
+type KafkaConfig struct {
+	Brokers   []string             `yaml:"brokers,omitempty" json:"brokers,omitempty"`
+	Topic     string               `yaml:"topic,omitempty" json:"topic,omitempty"`
+	SASL      *KafkaSASLConfig     `yaml:"sasl,omitempty" json:"sasl,omitempty"`
+	TLSConfig *commoncfg.TLSConfig `yaml:"tls_config,omitempty" json:"tls_config,omitempty"`
+}
+
+func (c *KafkaConfig) validate() error {
+	if len(c.Brokers) == 0 {
+		return fmt.Errorf("at least one broker must be configured for kafka protocol")
+	}
+	if c.Topic == "" {
+		return fmt.Errorf("topic must be configured for kafka protocol")
+	}
+	return nil
+}
+
+// NATSConfig configures the NATS CloudEvents protocol binding.
+type NATSConfig struct {
+	URL     string `yaml:"url,omitempty" json:"url,omitempty"`
+	Subject string `yaml:"subject,omitempty" json:"subject,omitempty"`
+}
+
+func (c *NATSConfig) validate() error {
+	if c.URL == "" {
+		return fmt.Errorf("url must be configured for nats protocol")
+	}
+	if c.Subject == "" {
+		return fmt.Errorf("subject must be configured for nats protocol")
+	}
+	return nil
+}
+
+// MQTTConfig configures the MQTT CloudEvents protocol binding.
+type MQTTConfig struct {
+	BrokerURL string           `yaml:"broker_url,omitempty" json:"broker_url,omitempty"`
+	Topic     string           `yaml:"topic,omitempty" json:"topic,omitempty"`
+	QoS       byte             `yaml:"qos,omitempty" json:"qos,omitempty"`
+	Username  string           `yaml:"username,omitempty" json:"username,omitempty"`
+	Password  commoncfg.Secret `yaml:"password,omitempty" json:"password,omitempty"`
+}
+
+func (c *MQTTConfig) validate() error {
+	if c.BrokerURL == "" {
+		return fmt.Errorf("broker_url must be configured for mqtt protocol")
+	}
+	if c.Topic == "" {
+		return fmt.Errorf("topic must be configured for mqtt protocol")
+	}
+	return nil
+}
+
+// PubSubConfig configures the Google Cloud Pub/Sub CloudEvents protocol
+// binding. Authentication is performed via Application Default Credentials.
+type PubSubConfig struct {
+	ProjectID string `yaml:"project_id,omitempty" json:"project_id,omitempty"`
+	TopicID   string `yaml:"topic_id,omitempty" json:"topic_id,omitempty"`
+}
+
+func (c *PubSubConfig) validate() error {
+	if c.ProjectID == "" {
+		return fmt.Errorf("project_id must be configured for gcppubsub protocol")
+	}
+	if c.TopicID == "" {
+		return fmt.Errorf("topic_id must be configured for gcppubsub protocol")
+	}
+	return nil
+}
+
+// EventConfig configures the CloudEvents notifier. The receiver protocol is
+// selected via Protocol, with the matching per-protocol block populated.
+type EventConfig struct {
+	NotifierConfig `yaml:",inline" json:",inline"`
+
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+
+	// Protocol selects the CloudEvents transport binding used to deliver
+	// events. Defaults to "http".
+	Protocol EventProtocol `yaml:"protocol,omitempty" json:"protocol,omitempty"`
+
+	// URL is the HTTP target and is required when Protocol is "http".
+	URL *URL `yaml:"url,omitempty" json:"url,omitempty"`
+
+	// ContentMode selects binary or structured HTTP content mode. Only
+	// applies when Protocol is "http".
+	ContentMode EventContentMode `yaml:"content_mode,omitempty" json:"content_mode,omitempty"`
+
+	// Source is the CloudEvents "source" attribute.
+	Source string `yaml:"source,omitempty" json:"source,omitempty"`
+
+	// Type, Subject and DataSchema are templated against the alert/group
+	// context before being attached to the outgoing CloudEvent.
+	Type            string `yaml:"type,omitempty" json:"type,omitempty"`
+	Subject         string `yaml:"subject,omitempty" json:"subject,omitempty"`
+	DataSchema      string `yaml:"data_schema,omitempty" json:"data_schema,omitempty"`
+	DataContentType string `yaml:"data_content_type,omitempty" json:"data_content_type,omitempty"`
+
+	// Extensions are CloudEvents extension attributes. Values are templated
+	// against the alert/group context.
+	Extensions map[string]string `yaml:"extensions,omitempty" json:"extensions,omitempty"`
+
+	// PerAlert, when true, emits one CloudEvent per alert instead of one per
+	// notification group.
+	PerAlert bool `yaml:"per_alert,omitempty" json:"per_alert,omitempty"`
+
+	Kafka  *KafkaConfig  `yaml:"kafka,omitempty" json:"kafka,omitempty"`
+	NATS   *NATSConfig   `yaml:"nats,omitempty" json:"nats,omitempty"`
+	MQTT   *MQTTConfig   `yaml:"mqtt,omitempty" json:"mqtt,omitempty"`
+	PubSub *PubSubConfig `yaml:"gcp_pubsub,omitempty" json:"gcp_pubsub,omitempty"`
+
+	MaxAlerts uint64 `yaml:"max_alerts,omitempty" json:"max_alerts,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *EventConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*c = DefaultEventConfig
+	type plain EventConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.Protocol == "" {
+		c.Protocol = ProtocolHTTP
+	}
+	switch c.Protocol {
+	case ProtocolHTTP:
+		if c.URL == nil {
+			return fmt.Errorf("url must be configured for http protocol")
+		}
+		switch c.ContentMode {
+		case ContentModeBinary, ContentModeStructured:
+		default:
+			return fmt.Errorf("unknown content mode %q", c.ContentMode)
+		}
+	case ProtocolKafka:
+		if c.Kafka == nil {
+			return fmt.Errorf("kafka block must be configured for kafka protocol")
+		}
+		if err := c.Kafka.validate(); err != nil {
+			return err
+		}
+	case ProtocolNATS:
+		if c.NATS == nil {
+			return fmt.Errorf("nats block must be configured for nats protocol")
+		}
+		if err := c.NATS.validate(); err != nil {
+			return err
+		}
+	case ProtocolMQTT:
+		if c.MQTT == nil {
+			return fmt.Errorf("mqtt block must be configured for mqtt protocol")
+		}
+		if err := c.MQTT.validate(); err != nil {
+			return err
+		}
+	case ProtocolGCPPubSub:
+		if c.PubSub == nil {
+			return fmt.Errorf("gcp_pubsub block must be configured for gcppubsub protocol")
+		}
+		if err := c.PubSub.validate(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown event protocol %q", c.Protocol)
+	}
+	return nil
+}
+
+// DefaultEventConfig defines default values for EventConfig.
+var DefaultEventConfig = EventConfig{
+	NotifierConfig: NotifierConfig{
+		VSendResolved: true,
+	},
+	Protocol:        ProtocolHTTP,
+	ContentMode:     ContentModeBinary,
+	Type:            "alert",
+	DataContentType: "application/json",
+}
+
+// EventFieldMapping extracts a single label or annotation value from an
+// incoming CloudEvent. DataPath takes precedence over Template when both are
+// set.
+type EventFieldMapping struct {
+	// Template is a Go text/template evaluated against the CloudEvent's
+	// attributes, extensions and parsed JSON data.
+	Template string `yaml:"template,omitempty" json:"template,omitempty"`
+
+	// DataPath is a dot-separated sequence of object keys (e.g.
+	// "resource.labels.pod") walked against the CloudEvent's parsed JSON
+	// data. It is not a full JSONPath expression: no array indexing,
+	// wildcards, or "$" root are supported.
+	DataPath string `yaml:"data_path,omitempty" json:"data_path,omitempty"`
+}
+
+// EventStatusMapping maps CloudEvents whose "type" attribute matches
+// TypeMatch (a filepath.Match glob, e.g. "*.resolved") to an alert Status.
+type EventStatusMapping struct {
+	TypeMatch string `yaml:"type_match,omitempty" json:"type_match,omitempty"`
+	Status    string `yaml:"status,omitempty" json:"status,omitempty"`
+}
+
+// EventReceiverConfig configures an ingress handler that accepts CloudEvents
+// and forwards them as alerts to an Alertmanager's /api/v2/alerts endpoint.
+type EventReceiverConfig struct {
+	// AlertmanagerURL is the base URL of the Alertmanager API that
+	// translated alerts are posted to.
+	AlertmanagerURL *URL `yaml:"alertmanager_url,omitempty" json:"alertmanager_url,omitempty"`
+
+	HTTPConfig *commoncfg.HTTPClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+
+	// Labels and Annotations map output label/annotation names to extraction
+	// rules evaluated against each incoming CloudEvent.
+	Labels      map[string]EventFieldMapping `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Annotations map[string]EventFieldMapping `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+
+	// StatusMappings maps CloudEvents "type" patterns to a firing/resolved
+	// status. The first match wins; unmatched events default to firing.
+	StatusMappings []EventStatusMapping `yaml:"status_mappings,omitempty" json:"status_mappings,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (c *EventReceiverConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain EventReceiverConfig
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	if c.AlertmanagerURL == nil {
+		return fmt.Errorf("alertmanager_url must be configured for an event receiver")
+	}
+	for name, m := range c.Labels {
+		if m.Template == "" && m.DataPath == "" {
+			return fmt.Errorf("label %q must configure template or data_path", name)
+		}
+	}
+	for name, m := range c.Annotations {
+		if m.Template == "" && m.DataPath == "" {
+			return fmt.Errorf("annotation %q must configure template or data_path", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2019 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestEventConfigUnmarshalYAMLProtocols(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		wantErr string
+	}{
+		{
+			name: "http with url is valid",
+			in:   `url: http://example.com/events`,
+		},
+		{
+			name:    "http requires url",
+			in:      `protocol: http`,
+			wantErr: "url must be configured",
+		},
+		{
+			name: "kafka requires brokers and topic",
+			in: `
+protocol: kafka
+kafka:
+  topic: alerts`,
+			wantErr: "at least one broker",
+		},
+		{
+			name: "kafka valid",
+			in: `
+protocol: kafka
+kafka:
+  brokers: ["broker:9092"]
+  topic: alerts`,
+		},
+		{
+			name: "nats requires url and subject",
+			in: `
+protocol: nats
+nats:
+  url: nats://localhost:4222`,
+			wantErr: "subject must be configured",
+		},
+		{
+			name: "mqtt requires broker_url and topic",
+			in: `
+protocol: mqtt
+mqtt:
+  topic: alerts`,
+			wantErr: "broker_url must be configured",
+		},
+		{
+			name: "gcppubsub requires project_id and topic_id",
+			in: `
+protocol: gcppubsub
+gcp_pubsub:
+  project_id: my-project`,
+			wantErr: "topic_id must be configured",
+		},
+		{
+			name:    "unknown protocol",
+			in:      `protocol: carrier-pigeon`,
+			wantErr: "unknown event protocol",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var c EventConfig
+			err := yaml.Unmarshal([]byte(tc.in), &c)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestEventConfigUnmarshalYAMLContentMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    EventContentMode
+		wantErr string
+	}{
+		{
+			name: "defaults to binary",
+			in:   `url: http://example.com/events`,
+			want: ContentModeBinary,
+		},
+		{
+			name: "structured accepted",
+			in: `
+url: http://example.com/events
+content_mode: structured`,
+			want: ContentModeStructured,
+		},
+		{
+			name: "unknown content mode rejected",
+			in: `
+url: http://example.com/events
+content_mode: compressed`,
+			wantErr: "unknown content mode",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var c EventConfig
+			err := yaml.Unmarshal([]byte(tc.in), &c)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if c.ContentMode != tc.want {
+				t.Fatalf("got content mode %q, want %q", c.ContentMode, tc.want)
+			}
+		})
+	}
+}
+
+func TestEventReceiverConfigUnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		wantErr string
+	}{
+		{
+			name:    "requires alertmanager_url",
+			in:      `labels: {}`,
+			wantErr: "alertmanager_url must be configured",
+		},
+		{
+			name: "label requires template or data_path",
+			in: `
+alertmanager_url: http://localhost:9093
+labels:
+  severity: {}`,
+			wantErr: `label "severity" must configure template or data_path`,
+		},
+		{
+			name: "annotation requires template or data_path",
+			in: `
+alertmanager_url: http://localhost:9093
+annotations:
+  summary: {}`,
+			wantErr: `annotation "summary" must configure template or data_path`,
+		},
+		{
+			name: "valid with data_path",
+			in: `
+alertmanager_url: http://localhost:9093
+labels:
+  severity:
+    data_path: resource.labels.severity`,
+		},
+		{
+			name: "valid with template",
+			in: `
+alertmanager_url: http://localhost:9093
+labels:
+  severity:
+    template: "{{ .Type }}"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var c EventReceiverConfig
+			err := yaml.Unmarshal([]byte(tc.in), &c)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
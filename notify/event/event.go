@@ -15,12 +15,16 @@ package event
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	commoncfg "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
 
 	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/notify"
@@ -30,29 +34,50 @@ import (
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 
+	cekafka "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	cemqtt "github.com/cloudevents/sdk-go/protocol/mqtt_paho/v2"
+	cenats "github.com/cloudevents/sdk-go/protocol/nats/v2"
+	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
+
+	"github.com/IBM/sarama"
+	paho "github.com/eclipse/paho.mqtt.golang"
+
 	"github.com/google/uuid"
 )
 
-// Notifier implements a Notifier for generic event.
+// Notifier implements a Notifier for generic event, sending CloudEvents
+// over a protocol binding selected by config.EventConfig.Protocol.
 type Notifier struct {
-	conf    *config.EventConfig
-	tmpl    *template.Template
-	logger  log.Logger
-	client  *http.Client
-	retrier *notify.Retrier
+	conf     *config.EventConfig
+	tmpl     *template.Template
+	logger   log.Logger
+	client   *http.Client
+	ceClient cloudevents.Client
+	retrier  *notify.Retrier
 }
 
 // New returns a new Event.
 func New(conf *config.EventConfig, t *template.Template, l log.Logger, httpOpts ...commoncfg.HTTPClientOption) (*Notifier, error) {
-	client, err := commoncfg.NewClientFromConfig(*conf.HTTPConfig, "event", httpOpts...)
+	var client *http.Client
+	if conf.HTTPConfig != nil {
+		var err error
+		client, err = commoncfg.NewClientFromConfig(*conf.HTTPConfig, "event", httpOpts...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ceClient, err := newCEClient(conf, client)
 	if err != nil {
 		return nil, err
 	}
+
 	return &Notifier{
-		conf:   conf,
-		tmpl:   t,
-		logger: l,
-		client: client,
+		conf:     conf,
+		tmpl:     t,
+		logger:   l,
+		client:   client,
+		ceClient: ceClient,
 		// Event are assumed to respond with 2xx response codes on a successful
 		// request and 5xx response codes are assumed to be recoverable.
 		retrier: &notify.Retrier{
@@ -63,6 +88,74 @@ func New(conf *config.EventConfig, t *template.Template, l log.Logger, httpOpts
 	}, nil
 }
 
+// newCEClient constructs, once per Notifier, the cloudevents-sdk-go
+// protocol.Sender matching conf.Protocol and wraps it in a cloudevents.Client.
+func newCEClient(conf *config.EventConfig, client *http.Client) (cloudevents.Client, error) {
+	switch conf.Protocol {
+	case config.ProtocolHTTP, "":
+		if client == nil {
+			// HTTPConfig is optional; fall back to a zero-value client so a
+			// minimal http-protocol config doesn't panic.
+			client = &http.Client{}
+		}
+		p, err := cehttp.New(cehttp.WithClient(*client), cehttp.WithTarget(conf.URL.String()))
+		if err != nil {
+			return nil, fmt.Errorf("create http protocol: %w", err)
+		}
+		if conf.ContentMode == config.ContentModeStructured {
+			return cloudevents.NewClient(p, cloudevents.WithEncodingStructured())
+		}
+		return cloudevents.NewClient(p, cloudevents.WithEncodingBinary())
+	case config.ProtocolKafka:
+		saramaConfig := sarama.NewConfig()
+		if sasl := conf.Kafka.SASL; sasl != nil {
+			saramaConfig.Net.SASL.Enable = true
+			saramaConfig.Net.SASL.Mechanism = sarama.SASLMechanism(sasl.Mechanism)
+			saramaConfig.Net.SASL.User = sasl.Username
+			saramaConfig.Net.SASL.Password = string(sasl.Password)
+		}
+		if conf.Kafka.TLSConfig != nil {
+			tlsConfig, err := commoncfg.NewTLSConfig(conf.Kafka.TLSConfig)
+			if err != nil {
+				return nil, fmt.Errorf("create kafka tls config: %w", err)
+			}
+			saramaConfig.Net.TLS.Enable = true
+			saramaConfig.Net.TLS.Config = tlsConfig
+		}
+		p, err := cekafka.NewSender(conf.Kafka.Brokers, saramaConfig, conf.Kafka.Topic)
+		if err != nil {
+			return nil, fmt.Errorf("create kafka protocol: %w", err)
+		}
+		return cloudevents.NewClient(p)
+	case config.ProtocolNATS:
+		p, err := cenats.NewSender(conf.NATS.URL, conf.NATS.Subject, cenats.NatsOptions())
+		if err != nil {
+			return nil, fmt.Errorf("create nats protocol: %w", err)
+		}
+		return cloudevents.NewClient(p)
+	case config.ProtocolMQTT:
+		opts := paho.NewClientOptions().AddBroker(conf.MQTT.BrokerURL)
+		if conf.MQTT.Username != "" {
+			opts.SetUsername(conf.MQTT.Username)
+			opts.SetPassword(string(conf.MQTT.Password))
+		}
+		mqttClient := paho.NewClient(opts)
+		p, err := cemqtt.New(context.Background(), mqttClient, conf.MQTT.Topic, conf.MQTT.Topic, cemqtt.WithQOS(byte(conf.MQTT.QoS)))
+		if err != nil {
+			return nil, fmt.Errorf("create mqtt protocol: %w", err)
+		}
+		return cloudevents.NewClient(p)
+	case config.ProtocolGCPPubSub:
+		p, err := cepubsub.New(context.Background(), cepubsub.WithProjectID(conf.PubSub.ProjectID), cepubsub.WithTopicID(conf.PubSub.TopicID))
+		if err != nil {
+			return nil, fmt.Errorf("create gcppubsub protocol: %w", err)
+		}
+		return cloudevents.NewClient(p)
+	default:
+		return nil, fmt.Errorf("unknown event protocol %q", conf.Protocol)
+	}
+}
+
 // Message defines the JSON object send to event endpoints.
 type Message struct {
 	*template.Data
@@ -84,6 +177,16 @@ func truncateAlerts(maxAlerts uint64, alerts []*types.Alert) ([]*types.Alert, ui
 // Notify implements the Notifier interface.
 func (n *Notifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
 	alerts, numTruncated := truncateAlerts(n.conf.MaxAlerts, alerts)
+
+	if n.conf.PerAlert {
+		return n.notifyPerAlert(ctx, alerts)
+	}
+	return n.notifyGroup(ctx, alerts, numTruncated)
+}
+
+// notifyGroup emits a single CloudEvent for the whole notification group.
+// This is the default, backward-compatible mode.
+func (n *Notifier) notifyGroup(ctx context.Context, alerts []*types.Alert, numTruncated uint64) (bool, error) {
 	data := notify.GetTemplateData(ctx, n.tmpl, alerts, n.logger)
 
 	groupKey, err := notify.ExtractGroupKey(ctx)
@@ -98,28 +201,138 @@ func (n *Notifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, er
 		TruncatedAlerts: numTruncated,
 	}
 
-	eventUuid := uuid.New()
-	event := cloudevents.NewEvent()
-	event.SetID(eventUuid.String())
-	event.SetSource(n.conf.Source)
-	event.SetType("alert")
-	event.SetData(cloudevents.ApplicationJSON, msg)
+	evType, err := n.tmpl.ExecuteTextString(n.conf.Type, data)
+	if err != nil {
+		return false, fmt.Errorf("execute type template: %w", err)
+	}
+	subject, err := n.tmpl.ExecuteTextString(n.conf.Subject, data)
+	if err != nil {
+		return false, fmt.Errorf("execute subject template: %w", err)
+	}
 
-	c, err := cloudevents.NewClientHTTP()
+	event, err := n.newEvent(uuid.New().String(), evType, subject, data, time.Time{}, time.Time{})
 	if err != nil {
 		return false, err
 	}
+	if err := event.SetData(n.contentType(), msg); err != nil {
+		return false, err
+	}
+
+	return n.send(ctx, event)
+}
 
-	ctx1 := cloudevents.ContextWithTarget(context.Background(), n.conf.URL.String())
+// notifyPerAlert emits one CloudEvent per alert, keyed by the alert's
+// fingerprint so downstream consumers can deduplicate.
+func (n *Notifier) notifyPerAlert(ctx context.Context, alerts []*types.Alert) (bool, error) {
+	var (
+		retry bool
+		errs  types.MultiError
+	)
 
-	// Send that Event.
-	result := c.Send(ctx1, event)
-	if cloudevents.IsUndelivered(result) {
-		return false, err
+	for _, alert := range alerts {
+		data := notify.GetTemplateData(ctx, n.tmpl, []*types.Alert{alert}, n.logger)
+
+		evType, err := n.tmpl.ExecuteTextString(n.conf.Type, data)
+		if err != nil {
+			errs.Add(fmt.Errorf("execute type template: %w", err))
+			continue
+		}
+		subject, err := n.tmpl.ExecuteTextString(n.conf.Subject, data)
+		if err != nil {
+			errs.Add(fmt.Errorf("execute subject template: %w", err))
+			continue
+		}
+
+		startsAt, endsAt := alert.StartsAt, time.Time{}
+		if alert.Status() == model.AlertResolved {
+			endsAt = alert.EndsAt
+		}
+
+		event, err := n.newEvent(alert.Fingerprint().String(), evType, subject, data, startsAt, endsAt)
+		if err != nil {
+			errs.Add(err)
+			continue
+		}
+		if err := event.SetData(n.contentType(), data); err != nil {
+			errs.Add(err)
+			continue
+		}
+
+		ok, err := n.send(ctx, event)
+		retry = retry || ok
+		if err != nil {
+			errs.Add(err)
+		}
+	}
+
+	if errs.Len() > 0 {
+		return retry, &errs
 	}
+	return false, nil
+}
+
+// contentType returns the configured CloudEvents data content type, falling
+// back to JSON.
+func (n *Notifier) contentType() string {
+	if n.conf.DataContentType != "" {
+		return n.conf.DataContentType
+	}
+	return cloudevents.ApplicationJSON
+}
 
-	var httpResult *cehttp.Result
-	cloudevents.ResultAs(result, &httpResult)
+// newEvent builds a CloudEvent populated with the id, type, subject,
+// data schema and extensions common to both emission modes.
+func (n *Notifier) newEvent(id, evType, subject string, data *template.Data, startsAt, endsAt time.Time) (cloudevents.Event, error) {
+	event := cloudevents.NewEvent()
+	event.SetID(id)
+	event.SetSource(n.conf.Source)
+	event.SetType(evType)
+	if subject != "" {
+		event.SetSubject(subject)
+	}
+	if n.conf.DataSchema != "" {
+		schema, err := n.tmpl.ExecuteTextString(n.conf.DataSchema, data)
+		if err != nil {
+			return event, fmt.Errorf("execute data_schema template: %w", err)
+		}
+		event.SetDataSchema(schema)
+	}
+	for k, v := range n.conf.Extensions {
+		rendered, err := n.tmpl.ExecuteTextString(v, data)
+		if err != nil {
+			return event, fmt.Errorf("execute extension %q template: %w", k, err)
+		}
+		event.SetExtension(k, rendered)
+	}
+	switch {
+	case !endsAt.IsZero():
+		// A non-zero endsAt means the alert has resolved; prefer it so the
+		// CloudEvent's time reflects when the alert actually ended.
+		event.SetTime(endsAt)
+	case !startsAt.IsZero():
+		event.SetTime(startsAt)
+	}
+	return event, nil
+}
+
+// send delivers the event over the protocol.Sender built once in New.
+func (n *Notifier) send(ctx context.Context, event cloudevents.Event) (bool, error) {
+	result := n.ceClient.Send(ctx, event)
+	if n.conf.Protocol == config.ProtocolHTTP || n.conf.Protocol == "" {
+		var httpResult *cehttp.Result
+		if cloudevents.ResultAs(result, &httpResult) {
+			return n.retrier.Check(httpResult.StatusCode, strings.NewReader(httpResult.Error()))
+		}
+		return false, result
+	}
+
+	if cloudevents.IsUndelivered(result) {
+		// Transient broker errors are retried by the notify pipeline.
+		return true, result
+	}
+	if !cloudevents.IsACK(result) {
+		return false, result
+	}
 
-	return n.retrier.Check(httpResult.StatusCode, nil)
+	return false, nil
 }
@@ -0,0 +1,181 @@
+// Copyright 2019 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+)
+
+func newTestNotifier(t *testing.T, target string, mutate func(*config.EventConfig)) *Notifier {
+	t.Helper()
+
+	tmpl, err := template.FromGlobs(nil)
+	if err != nil {
+		t.Fatalf("template.FromGlobs: %v", err)
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+
+	conf := &config.EventConfig{
+		Protocol:        config.ProtocolHTTP,
+		ContentMode:     config.ContentModeBinary,
+		URL:             &config.URL{URL: u},
+		Source:          "alertmanager",
+		Type:            "io.prometheus.alert.{{ .Status }}",
+		DataContentType: "application/json",
+	}
+	if mutate != nil {
+		mutate(conf)
+	}
+
+	n, err := New(conf, tmpl, log.NewNopLogger())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return n
+}
+
+func testAlert(resolved bool) *types.Alert {
+	startsAt := time.Now().Add(-time.Hour)
+	a := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "test"},
+			StartsAt: startsAt,
+		},
+	}
+	if resolved {
+		a.Alert.EndsAt = startsAt.Add(time.Minute)
+	}
+	return a
+}
+
+func TestNotifyGroupSendsSingleEvent(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	n := newTestNotifier(t, srv.URL, nil)
+	ctx := notify.WithGroupKey(context.Background(), "group-1")
+
+	retry, err := n.Notify(ctx, testAlert(false), testAlert(true))
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if retry {
+		t.Fatalf("expected retry=false on success")
+	}
+	if requests != 1 {
+		t.Fatalf("expected 1 request for group mode, got %d", requests)
+	}
+}
+
+func TestNotifyPerAlertSendsOneEventPerAlert(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	n := newTestNotifier(t, srv.URL, func(c *config.EventConfig) {
+		c.PerAlert = true
+	})
+	ctx := notify.WithGroupKey(context.Background(), "group-1")
+
+	retry, err := n.Notify(ctx, testAlert(false), testAlert(true))
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if retry {
+		t.Fatalf("expected retry=false on success")
+	}
+	if requests != 2 {
+		t.Fatalf("expected 2 requests for per-alert mode, got %d", requests)
+	}
+}
+
+func TestNotifyRetriesOn5xx(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "upstream unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	n := newTestNotifier(t, srv.URL, nil)
+	ctx := notify.WithGroupKey(context.Background(), "group-1")
+
+	retry, err := n.Notify(ctx, testAlert(false))
+	if err == nil {
+		t.Fatalf("expected an error for a 5xx response")
+	}
+	if !retry {
+		t.Fatalf("expected retry=true for a 5xx response")
+	}
+}
+
+func TestNewCEClientFallsBackWhenHTTPConfigUnset(t *testing.T) {
+	u, err := url.Parse("http://example.invalid")
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+	conf := &config.EventConfig{
+		Protocol: config.ProtocolHTTP,
+		URL:      &config.URL{URL: u},
+	}
+
+	if _, err := newCEClient(conf, nil); err != nil {
+		t.Fatalf("newCEClient with nil http client: %v", err)
+	}
+}
+
+func TestNewEventTimePrefersEndsAtWhenResolved(t *testing.T) {
+	n := newTestNotifier(t, "http://example.invalid", nil)
+
+	startsAt := time.Now().Add(-time.Hour)
+	endsAt := startsAt.Add(time.Minute)
+
+	resolvedEvent, err := n.newEvent("id-1", "alert", "", nil, startsAt, endsAt)
+	if err != nil {
+		t.Fatalf("newEvent: %v", err)
+	}
+	if !resolvedEvent.Time().Equal(endsAt) {
+		t.Fatalf("resolved event time = %v, want %v (EndsAt)", resolvedEvent.Time(), endsAt)
+	}
+
+	firingEvent, err := n.newEvent("id-2", "alert", "", nil, startsAt, time.Time{})
+	if err != nil {
+		t.Fatalf("newEvent: %v", err)
+	}
+	if !firingEvent.Time().Equal(startsAt) {
+		t.Fatalf("firing event time = %v, want %v (StartsAt)", firingEvent.Time(), startsAt)
+	}
+}
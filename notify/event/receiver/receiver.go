@@ -0,0 +1,275 @@
+// Copyright 2019 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package receiver implements a CloudEvents ingress for Alertmanager: it
+// accepts CloudEvents over HTTP (binary or structured content mode) and
+// forwards them as alerts to an Alertmanager's /api/v2/alerts endpoint,
+// letting Alertmanager participate as a sink in CloudEvents-based event
+// meshes the same way notify/event lets it participate as a source.
+package receiver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	commoncfg "github.com/prometheus/common/config"
+
+	"github.com/prometheus/alertmanager/config"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+)
+
+// dedupeTTL bounds how long a CloudEvent ID is remembered for deduplication.
+const dedupeTTL = 10 * time.Minute
+
+// postableAlert mirrors the subset of the /api/v2/alerts request body that
+// the receiver populates from an incoming CloudEvent.
+type postableAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     *time.Time        `json:"startsAt,omitempty"`
+	EndsAt       *time.Time        `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// fieldContext is the data made available to Template and DataPath
+// extraction rules.
+type fieldContext struct {
+	ID      string
+	Source  string
+	Type    string
+	Subject string
+
+	Extensions map[string]interface{}
+	Data       interface{}
+}
+
+// Handler receives CloudEvents and forwards them as alerts to Alertmanager.
+type Handler struct {
+	conf      *config.EventReceiverConfig
+	logger    log.Logger
+	client    *http.Client
+	ceHandler http.Handler
+
+	mtx  sync.Mutex
+	seen map[string]time.Time
+}
+
+// New returns a Handler that accepts CloudEvents in both the binary and
+// structured HTTP content modes.
+func New(conf *config.EventReceiverConfig, l log.Logger, httpOpts ...commoncfg.HTTPClientOption) (*Handler, error) {
+	var client *http.Client
+	if conf.HTTPConfig != nil {
+		var err error
+		client, err = commoncfg.NewClientFromConfig(*conf.HTTPConfig, "event_receiver", httpOpts...)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		client = &http.Client{}
+	}
+
+	h := &Handler{
+		conf:   conf,
+		logger: l,
+		client: client,
+		seen:   make(map[string]time.Time),
+	}
+
+	p, err := cehttp.New()
+	if err != nil {
+		return nil, fmt.Errorf("create http protocol: %w", err)
+	}
+	ceHandler, err := cloudevents.NewHTTPReceiveHandler(context.Background(), p, h.receive)
+	if err != nil {
+		return nil, fmt.Errorf("create receive handler: %w", err)
+	}
+	h.ceHandler = ceHandler
+
+	return h, nil
+}
+
+// ServeHTTP implements http.Handler, decoding both binary and structured
+// CloudEvents HTTP requests.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.ceHandler.ServeHTTP(w, r)
+}
+
+// receive is invoked by the CloudEvents SDK once per decoded event.
+func (h *Handler) receive(ctx context.Context, event cloudevents.Event) cloudevents.Result {
+	if h.seenBefore(event.ID()) {
+		level.Debug(h.logger).Log("msg", "duplicate CloudEvent, skipping", "id", event.ID())
+		return cloudevents.ResultACK
+	}
+
+	fctx := fieldContext{
+		ID:         event.ID(),
+		Source:     event.Source(),
+		Type:       event.Type(),
+		Subject:    event.Subject(),
+		Extensions: event.Extensions(),
+	}
+	if raw := event.Data(); len(raw) > 0 {
+		if err := json.Unmarshal(raw, &fctx.Data); err != nil {
+			level.Warn(h.logger).Log("msg", "failed to parse CloudEvent data as JSON", "id", event.ID(), "err", err)
+		}
+	}
+
+	alert := postableAlert{
+		Labels:       map[string]string{},
+		Annotations:  map[string]string{},
+		GeneratorURL: event.Source(),
+	}
+	for name, m := range h.conf.Labels {
+		v, err := evalField(m, fctx)
+		if err != nil {
+			level.Warn(h.logger).Log("msg", "failed to extract label", "label", name, "id", event.ID(), "err", err)
+			continue
+		}
+		alert.Labels[name] = v
+	}
+	for name, m := range h.conf.Annotations {
+		v, err := evalField(m, fctx)
+		if err != nil {
+			level.Warn(h.logger).Log("msg", "failed to extract annotation", "annotation", name, "id", event.ID(), "err", err)
+			continue
+		}
+		alert.Annotations[name] = v
+	}
+
+	if t := event.Time(); !t.IsZero() {
+		alert.StartsAt = &t
+	}
+	if h.resolveStatus(event.Type()) == "resolved" {
+		if alert.StartsAt == nil {
+			now := time.Now()
+			alert.StartsAt = &now
+		}
+		alert.EndsAt = alert.StartsAt
+	}
+
+	if err := h.postAlert(ctx, alert); err != nil {
+		level.Error(h.logger).Log("msg", "failed to post alert", "id", event.ID(), "err", err)
+		return cehttp.NewResult(http.StatusInternalServerError, "post alert: %w", err)
+	}
+	return cloudevents.ResultACK
+}
+
+// resolveStatus returns the alert status ("firing" or "resolved") for a
+// CloudEvent "type", matching StatusMappings in order. Unmatched types
+// default to firing.
+func (h *Handler) resolveStatus(evType string) string {
+	for _, m := range h.conf.StatusMappings {
+		if ok, _ := filepath.Match(m.TypeMatch, evType); ok {
+			return m.Status
+		}
+	}
+	return "firing"
+}
+
+// seenBefore reports whether id was seen within dedupeTTL, recording it if
+// not.
+func (h *Handler) seenBefore(id string) bool {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	now := time.Now()
+	for k, t := range h.seen {
+		if now.Sub(t) > dedupeTTL {
+			delete(h.seen, k)
+		}
+	}
+	if _, ok := h.seen[id]; ok {
+		return true
+	}
+	h.seen[id] = now
+	return false
+}
+
+// evalField extracts a label/annotation value from fctx, preferring
+// DataPath over Template when both are configured.
+func evalField(m config.EventFieldMapping, fctx fieldContext) (string, error) {
+	if m.DataPath != "" {
+		v, err := dataPath(m.DataPath, fctx.Data)
+		if err != nil {
+			return "", fmt.Errorf("data_path %q: %w", m.DataPath, err)
+		}
+		return fmt.Sprintf("%v", v), nil
+	}
+
+	tmpl, err := template.New("field").Parse(m.Template)
+	if err != nil {
+		return "", fmt.Errorf("parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, fctx); err != nil {
+		return "", fmt.Errorf("execute template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// dataPath resolves a dot-separated sequence of object keys (e.g.
+// "resource.labels.pod") against decoded JSON data. It is deliberately not a
+// JSONPath implementation: no array indexing, wildcards, or "$" root.
+func dataPath(path string, data interface{}) (interface{}, error) {
+	cur := data
+	for _, key := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%q is not an object", key)
+		}
+		v, ok := m[key]
+		if !ok {
+			return nil, fmt.Errorf("key %q not found", key)
+		}
+		cur = v
+	}
+	return cur, nil
+}
+
+// postAlert posts alert to the configured Alertmanager's /api/v2/alerts.
+func (h *Handler) postAlert(ctx context.Context, alert postableAlert) error {
+	body, err := json.Marshal([]postableAlert{alert})
+	if err != nil {
+		return fmt.Errorf("marshal alert: %w", err)
+	}
+
+	target := strings.TrimRight(h.conf.AlertmanagerURL.String(), "/") + "/api/v2/alerts"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, target)
+	}
+	return nil
+}
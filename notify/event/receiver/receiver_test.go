@@ -0,0 +1,193 @@
+// Copyright 2019 Prometheus Team
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receiver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+
+	"github.com/prometheus/alertmanager/config"
+)
+
+func newTestHandler(t *testing.T, conf *config.EventReceiverConfig) *Handler {
+	t.Helper()
+	h, err := New(conf, log.NewNopLogger())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return h
+}
+
+func TestNewFallsBackWhenHTTPConfigUnset(t *testing.T) {
+	amURL, err := url.Parse("http://example.invalid")
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+
+	if _, err := New(&config.EventReceiverConfig{AlertmanagerURL: &config.URL{URL: amURL}}, log.NewNopLogger()); err != nil {
+		t.Fatalf("New with nil HTTPConfig: %v", err)
+	}
+}
+
+func TestDataPath(t *testing.T) {
+	data := map[string]interface{}{
+		"resource": map[string]interface{}{
+			"labels": map[string]interface{}{
+				"pod": "web-1",
+			},
+		},
+	}
+
+	got, err := dataPath("resource.labels.pod", data)
+	if err != nil {
+		t.Fatalf("dataPath: %v", err)
+	}
+	if got != "web-1" {
+		t.Fatalf("got %v, want web-1", got)
+	}
+
+	if _, err := dataPath("resource.labels.missing", data); err == nil {
+		t.Fatalf("expected error for missing key")
+	}
+}
+
+func TestResolveStatus(t *testing.T) {
+	h := &Handler{
+		conf: &config.EventReceiverConfig{
+			StatusMappings: []config.EventStatusMapping{
+				{TypeMatch: "*.resolved", Status: "resolved"},
+			},
+		},
+	}
+
+	if got := h.resolveStatus("io.prometheus.alert.resolved"); got != "resolved" {
+		t.Fatalf("got %q, want resolved", got)
+	}
+	if got := h.resolveStatus("io.prometheus.alert.firing"); got != "firing" {
+		t.Fatalf("got %q, want firing", got)
+	}
+}
+
+func TestSeenBeforeDedupesByID(t *testing.T) {
+	h := &Handler{seen: make(map[string]time.Time)}
+
+	if h.seenBefore("evt-1") {
+		t.Fatalf("first sighting of evt-1 should not be deduped")
+	}
+	if !h.seenBefore("evt-1") {
+		t.Fatalf("second sighting of evt-1 should be deduped")
+	}
+}
+
+func TestSeenBeforeExpiresAfterTTL(t *testing.T) {
+	h := &Handler{seen: make(map[string]time.Time)}
+	h.seen["evt-1"] = time.Now().Add(-dedupeTTL - time.Second)
+
+	if h.seenBefore("evt-1") {
+		t.Fatalf("expired entry should not be treated as a duplicate")
+	}
+}
+
+func TestEvalFieldTemplateAndDataPath(t *testing.T) {
+	fctx := fieldContext{
+		Type: "io.prometheus.alert.firing",
+		Data: map[string]interface{}{"severity": "critical"},
+	}
+
+	got, err := evalField(config.EventFieldMapping{Template: "{{ .Type }}"}, fctx)
+	if err != nil {
+		t.Fatalf("evalField template: %v", err)
+	}
+	if got != "io.prometheus.alert.firing" {
+		t.Fatalf("got %q, want io.prometheus.alert.firing", got)
+	}
+
+	got, err = evalField(config.EventFieldMapping{DataPath: "severity"}, fctx)
+	if err != nil {
+		t.Fatalf("evalField data_path: %v", err)
+	}
+	if got != "critical" {
+		t.Fatalf("got %q, want critical", got)
+	}
+}
+
+func TestServeHTTPForwardsAlertAndDedupes(t *testing.T) {
+	var postCount int
+	var lastAlerts []map[string]interface{}
+
+	am := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		postCount++
+		if err := json.NewDecoder(r.Body).Decode(&lastAlerts); err != nil {
+			t.Errorf("decode posted alert: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer am.Close()
+
+	amURL, err := url.Parse(am.URL)
+	if err != nil {
+		t.Fatalf("url.Parse: %v", err)
+	}
+
+	conf := &config.EventReceiverConfig{
+		AlertmanagerURL: &config.URL{URL: amURL},
+		Labels: map[string]config.EventFieldMapping{
+			"alertname": {Template: "{{ .Type }}"},
+			"pod":       {DataPath: "resource.labels.pod"},
+		},
+	}
+	h := newTestHandler(t, conf)
+
+	newRequest := func() *http.Request {
+		body := `{"resource":{"labels":{"pod":"web-1"}}}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Ce-Specversion", "1.0")
+		req.Header.Set("Ce-Id", "evt-1")
+		req.Header.Set("Ce-Source", "test-source")
+		req.Header.Set("Ce-Type", "io.prometheus.alert.firing")
+		return req
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newRequest())
+	if rec.Code < 200 || rec.Code >= 300 {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body.String())
+	}
+	if postCount != 1 {
+		t.Fatalf("expected 1 alert posted to alertmanager, got %d", postCount)
+	}
+	if len(lastAlerts) != 1 {
+		t.Fatalf("expected 1 alert in request body, got %d", len(lastAlerts))
+	}
+	labels, _ := lastAlerts[0]["labels"].(map[string]interface{})
+	if labels["pod"] != "web-1" {
+		t.Fatalf("got labels %v, want pod=web-1", labels)
+	}
+
+	// Replaying the same CloudEvent ID should be deduplicated and not
+	// forwarded again.
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, newRequest())
+	if postCount != 1 {
+		t.Fatalf("expected duplicate CloudEvent to be deduped, got %d posts", postCount)
+	}
+}